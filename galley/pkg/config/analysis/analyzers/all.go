@@ -0,0 +1,32 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analyzers defines the registry of all Istio config analyzers.
+package analyzers
+
+import (
+	"istio.io/istio/galley/pkg/config/analysis"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+)
+
+// All returns all analyzers known to Galley.
+func All() []analysis.Analyzer {
+	return []analysis.Analyzer{
+		&gateway.IngressGatewayPortAnalyzer{},
+		&gateway.GatewayAPIPortAnalyzer{},
+		&gateway.GatewayConflictAnalyzer{},
+		&gateway.GatewayTLSCredentialAnalyzer{},
+		&gateway.EgressGatewayPortAnalyzer{},
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"sort"
+	"strings"
+
+	"istio.io/api/networking/v1alpha3"
+
+	"istio.io/istio/galley/pkg/config/analysis"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+	"istio.io/istio/galley/pkg/config/resource"
+	"istio.io/istio/galley/pkg/config/schema/collection"
+	"istio.io/istio/galley/pkg/config/schema/collections"
+)
+
+// GatewayConflictAnalyzer reports Gateways that share a workload selector but bind
+// the same (port, protocol) to overlapping hosts or TLS SNI matchers, which causes
+// Envoy to silently drop one of the conflicting listener filter chains.
+type GatewayConflictAnalyzer struct{}
+
+// (compile-time check that we implement the interface)
+var _ analysis.Analyzer = &GatewayConflictAnalyzer{}
+
+// Metadata implements analysis.Analyzer
+func (*GatewayConflictAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "gateway.GatewayConflictAnalyzer",
+		Description: "Checks for Gateways on the same workload that bind the same port/protocol to overlapping hosts",
+		Inputs: collection.Names{
+			collections.IstioNetworkingV1Alpha3Gateways.Name(),
+		},
+	}
+}
+
+type gatewayEntry struct {
+	instance *resource.Instance
+	gw       *v1alpha3.Gateway
+}
+
+type gatewayServer struct {
+	entry  gatewayEntry
+	server *v1alpha3.Server
+}
+
+type portProtocolKey struct {
+	port     uint32
+	protocol string
+}
+
+// Analyze implements analysis.Analyzer
+func (s *GatewayConflictAnalyzer) Analyze(c analysis.Context) {
+	bySelector := map[string][]gatewayEntry{}
+	c.ForEach(collections.IstioNetworkingV1Alpha3Gateways.Name(), func(r *resource.Instance) bool {
+		gw := r.Message.(*v1alpha3.Gateway)
+		key := normalizeSelector(gw.Selector)
+		bySelector[key] = append(bySelector[key], gatewayEntry{instance: r, gw: gw})
+		return true
+	})
+
+	for _, entries := range bySelector {
+		if len(entries) < 2 {
+			// A selector with a single Gateway can't conflict with itself.
+			continue
+		}
+		s.analyzeGroup(entries, c)
+	}
+}
+
+func (*GatewayConflictAnalyzer) analyzeGroup(entries []gatewayEntry, c analysis.Context) {
+	buckets := map[portProtocolKey][]gatewayServer{}
+	for _, e := range entries {
+		for _, server := range e.gw.Servers {
+			if server.Port == nil {
+				continue
+			}
+			key := portProtocolKey{port: server.Port.Number, protocol: strings.ToUpper(server.Port.Protocol)}
+			buckets[key] = append(buckets[key], gatewayServer{entry: e, server: server})
+		}
+	}
+
+	for _, servers := range buckets {
+		for i := 0; i < len(servers); i++ {
+			for j := i + 1; j < len(servers); j++ {
+				if servers[i].entry.instance == servers[j].entry.instance {
+					continue
+				}
+				host, otherHost, ok := overlappingHosts(servers[i].server.Hosts, servers[j].server.Hosts)
+				if !ok {
+					continue
+				}
+				c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(),
+					msg.NewConflictingGatewayHost(servers[i].entry.instance, servers[j].entry.instance.Metadata.FullName.String(), host, otherHost))
+			}
+		}
+	}
+}
+
+// normalizeSelector produces a stable string key for a workload selector so that
+// Gateways selecting the same workload(s) can be grouped together, regardless of
+// the order their labels were declared in.
+func normalizeSelector(selector map[string]string) string {
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(selector[k])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// overlappingHosts reports the first pair of hosts from a and b (each possibly of the
+// form "namespace/host" and possibly a wildcard like "*.example.com") that overlap.
+func overlappingHosts(a, b []string) (string, string, bool) {
+	for _, ha := range a {
+		for _, hb := range b {
+			if hostOverlap(ha, hb) {
+				return ha, hb, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func hostOverlap(a, b string) bool {
+	a, b = stripNamespace(a), stripNamespace(b)
+	if a == b {
+		return true
+	}
+	return wildcardCovers(a, b) || wildcardCovers(b, a)
+}
+
+// wildcardCovers reports whether host wildcard (e.g. "*.example.com" or "*") covers host.
+// When host is itself a "*." wildcard, it reports whether the two wildcards overlap.
+func wildcardCovers(wildcard, host string) bool {
+	if wildcard == "*" {
+		return true
+	}
+	if !strings.HasPrefix(wildcard, "*.") {
+		return false
+	}
+	suffix := wildcard[1:] // ".example.com"
+	if strings.HasPrefix(host, "*.") {
+		// Two wildcards overlap if either's suffix is a suffix of the other's.
+		hostSuffix := host[1:]
+		return strings.HasSuffix(hostSuffix, suffix) || strings.HasSuffix(suffix, hostSuffix)
+	}
+	return strings.HasSuffix(host, suffix)
+}
+
+func stripNamespace(host string) string {
+	if idx := strings.IndexByte(host, '/'); idx >= 0 {
+		return host[idx+1:]
+	}
+	return host
+}
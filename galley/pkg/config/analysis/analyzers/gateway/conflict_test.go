@@ -0,0 +1,49 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/testutil"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+)
+
+func TestGatewayConflict(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := testutil.NewContext(t, "testdata/gateway-conflict.yaml")
+	a := &gateway.GatewayConflictAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.ConflictingGatewayHost))
+}
+
+func TestGatewayConflictClean(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// Disjoint hosts on the same selector/port/protocol, plus a third gateway that's
+	// alone on its own selector, shouldn't report anything.
+	ctx := testutil.NewContext(t, "testdata/gateway-conflict-clean.yaml")
+	a := &gateway.GatewayConflictAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
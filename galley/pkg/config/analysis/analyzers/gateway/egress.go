@@ -0,0 +1,119 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"strings"
+
+	k8s_labels "k8s.io/apimachinery/pkg/labels"
+
+	"istio.io/api/networking/v1alpha3"
+
+	"istio.io/istio/galley/pkg/config/analysis"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+	"istio.io/istio/galley/pkg/config/resource"
+	"istio.io/istio/galley/pkg/config/schema/collection"
+	"istio.io/istio/galley/pkg/config/schema/collections"
+)
+
+// EgressGatewayPortAnalyzer checks a gateway's ports against the Kubernetes service
+// ports of the egress gateway workload it selects.
+type EgressGatewayPortAnalyzer struct{}
+
+var (
+	// The ports from install/kubernetes/istio.yaml's istio-egressgateway service.
+	// Use this only if we validate a Gateway that selects the system egress gateway and
+	// the user doesn't supply it.
+	defaultEgressGatewayPorts = map[uint32]bool{
+		80:  true,
+		443: true,
+	}
+
+	// (compile-time check that we implement the interface)
+	_ analysis.Analyzer = &EgressGatewayPortAnalyzer{}
+)
+
+// Metadata implements analysis.Analyzer
+func (*EgressGatewayPortAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "gateway.EgressGatewayPortAnalyzer",
+		Description: "Checks a gateway's ports against the egress gateway's Kubernetes service ports",
+		Inputs: collection.Names{
+			collections.IstioNetworkingV1Alpha3Gateways.Name(),
+			collections.K8SCoreV1Pods.Name(),
+			collections.K8SCoreV1Services.Name(),
+		},
+	}
+}
+
+// Analyze implements analysis.Analyzer
+func (s *EgressGatewayPortAnalyzer) Analyze(c analysis.Context) {
+	c.ForEach(collections.IstioNetworkingV1Alpha3Gateways.Name(), func(r *resource.Instance) bool {
+		s.analyzeGateway(r, c)
+		return true
+	})
+}
+
+func (*EgressGatewayPortAnalyzer) analyzeGateway(r *resource.Instance, c analysis.Context) {
+	gw := r.Message.(*v1alpha3.Gateway)
+
+	if !selectorLooksLikeEgress(gw.Selector) {
+		// This Gateway's selector doesn't plausibly target an egress workload, so leave
+		// it to IngressGatewayPortAnalyzer. Without this check both analyzers would
+		// reconcile the same selector and double-report any genuine port mismatch.
+		return
+	}
+
+	gwSelector := k8s_labels.SelectorFromSet(gw.Selector)
+
+	servicePorts, gwSelectorMatches := collectSelectedServicePorts(gw.Selector, c)
+
+	if gwSelectorMatches == 0 {
+		// We found no pod for the Gateway's workload selector. If the Gateway doesn't
+		// select the Istio system egress gateway, complain about a missing referenced
+		// resource, same as IngressGatewayPortAnalyzer does for the ingress side.
+		if len(gw.Selector) != 1 || gw.Selector["istio"] != "egressgateway" {
+			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(), msg.NewReferencedResourceNotFound(r, "selector", gwSelector.String()))
+			return
+		}
+		// The unreferenced Gateway is the System egress gateway, pretend we have found it.
+		servicePorts = map[uint32]servicePortInfo{}
+		for port := range defaultEgressGatewayPorts {
+			servicePorts[port] = servicePortInfo{}
+		}
+	}
+
+	for _, server := range gw.Servers {
+		if server.Port == nil {
+			continue
+		}
+		if _, ok := servicePorts[server.Port.Number]; !ok {
+			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(),
+				msg.NewGatewayPortNotOnWorkload(r, gwSelector.String(), int(server.Port.Number)))
+		}
+	}
+}
+
+// selectorLooksLikeEgress reports whether selector plausibly targets an egress gateway
+// workload (e.g. "istio: egressgateway"), so that EgressGatewayPortAnalyzer only ever
+// reconciles Gateways that IngressGatewayPortAnalyzer wouldn't already cover.
+func selectorLooksLikeEgress(selector map[string]string) bool {
+	for k, v := range selector {
+		if strings.Contains(strings.ToLower(k), "egress") || strings.Contains(strings.ToLower(v), "egress") {
+			return true
+		}
+	}
+	return false
+}
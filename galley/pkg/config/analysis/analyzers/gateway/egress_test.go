@@ -0,0 +1,62 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/testutil"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+)
+
+func TestEgressGatewayPort(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := testutil.NewContext(t, "testdata/egress-gateway-port.yaml")
+	a := &gateway.EgressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.GatewayPortNotOnWorkload))
+}
+
+func TestEgressGatewayPortClean(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A port that matches both number and protocol on an egress-selector Gateway
+	// shouldn't be reported at all.
+	ctx := testutil.NewContext(t, "testdata/egress-gateway-port-clean.yaml")
+	a := &gateway.EgressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
+
+func TestEgressGatewayPortIgnoresIngressSelector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A Gateway selecting istio=ingressgateway has a genuine port mismatch, but it's
+	// IngressGatewayPortAnalyzer's job to report it -- EgressGatewayPortAnalyzer must
+	// stay out of the way to avoid a duplicate diagnostic.
+	ctx := testutil.NewContext(t, "testdata/egress-gateway-ignores-ingress-selector.yaml")
+	a := &gateway.EgressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
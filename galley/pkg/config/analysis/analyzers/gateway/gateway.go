@@ -15,6 +15,8 @@
 package gateway
 
 import (
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
 	k8s_labels "k8s.io/apimachinery/pkg/labels"
 
@@ -70,15 +72,133 @@ func (*IngressGatewayPortAnalyzer) analyzeGateway(r *resource.Instance, c analys
 
 	gw := r.Message.(*v1alpha3.Gateway)
 
+	if selectorLooksLikeEgress(gw.Selector) {
+		// This Gateway's selector plausibly targets an egress workload; leave it to
+		// EgressGatewayPortAnalyzer so the two analyzers don't double-report the same
+		// Gateway.
+		return
+	}
+
 	// Typically there will be a single istio-ingressgateway service, which will select
 	// the same ingress gateway pod workload as the Gateway resource.  If there are multiple
 	// Kubernetes services, and they offer different TCP port combinations, this validator will
 	// not report a problem if *any* selecting service exposes the Gateway's port.
-	servicePorts := map[uint32]bool{}
+	servicePorts, gwSelectorMatches := collectSelectedServicePorts(gw.Selector, c)
+	gwSelector := k8s_labels.SelectorFromSet(gw.Selector)
+
+	if gwSelectorMatches == 0 {
+		// We found no pod for the Gateway's workload selector.  If the Gateway doesn't select
+		// the Istio system ingress gateway complain about a missing referenced resource.  (We
+		// don't want to complain about missing system resources, because a user may want to analyze
+		// only his own application files.)
+		// https://github.com/istio/istio/issues/19579 should make this unnecessary
+		if len(gw.Selector) != 1 || gw.Selector["istio"] != "ingressgateway" {
+			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(), msg.NewReferencedResourceNotFound(r, "selector", gwSelector.String()))
+			return
+		}
+		// The unreferenced Ingress is the System ingress, pretend we have found it.
+		servicePorts = map[uint32]servicePortInfo{}
+		for port := range defaultIngressGatewayPorts {
+			servicePorts[port] = servicePortInfo{}
+		}
+	}
+
+	// Check each Gateway port against what the workload ingress service offers
+	for _, server := range gw.Servers {
+		if server.Port == nil {
+			continue
+		}
+		svcPort, ok := servicePorts[server.Port.Number]
+		if !ok {
+			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(), msg.NewGatewayPortNotOnWorkload(r, gwSelector.String(), int(server.Port.Number)))
+			continue
+		}
+		if svcPort.name == "" && svcPort.appProtocol == "" {
+			// No Service port name/appProtocol to compare against (e.g. the default
+			// system ingress gateway ports), so there's nothing further to validate.
+			continue
+		}
+		if expected := protocolFromServicePort(svcPort); expected != "" && !protocolsCompatible(string(server.Port.Protocol), expected) {
+			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(),
+				msg.NewGatewayPortProtocolMismatch(r, string(server.Port.Protocol), expected, svcPort.serviceName, int(server.Port.Number)))
+		}
+	}
+}
+
+// servicePortInfo carries the bits of a Kubernetes Service port needed to validate
+// it against a Gateway server's declared protocol.
+type servicePortInfo struct {
+	serviceName string
+	name        string
+	appProtocol string
+}
+
+// protocolPrefixes maps an Istio Gateway server protocol to the Service port name
+// prefixes (https://istio.io/latest/docs/ops/configuration/traffic-management/protocol-selection/)
+// that are considered compatible with it.
+var protocolPrefixes = map[string][]string{
+	"HTTP":  {"http"},
+	"HTTP2": {"http2", "http"},
+	"HTTPS": {"https"},
+	"TLS":   {"tls", "https"},
+	"GRPC":  {"grpc"},
+	"TCP":   {"tcp"},
+}
+
+// knownServicePortPrefixes are the well-known Istio protocol names a Service port can be
+// named after, either exactly (e.g. "https") or as a dash-separated prefix (e.g. "https-foo").
+var knownServicePortPrefixes = map[string]bool{
+	"http":  true,
+	"http2": true,
+	"https": true,
+	"tls":   true,
+	"grpc":  true,
+	"tcp":   true,
+}
+
+// protocolFromServicePort derives the effective protocol of a Service port, preferring
+// the explicit appProtocol field and falling back to the well-known name prefix -- the
+// name may be the protocol by itself (e.g. "https") or a dash-separated prefix of it
+// (e.g. "https-foo").
+func protocolFromServicePort(p servicePortInfo) string {
+	if p.appProtocol != "" {
+		return strings.ToUpper(p.appProtocol)
+	}
+	name := strings.ToLower(p.name)
+	if idx := strings.IndexRune(name, '-'); idx > 0 {
+		name = name[:idx]
+	}
+	if knownServicePortPrefixes[name] {
+		return strings.ToUpper(name)
+	}
+	return ""
+}
+
+// protocolsCompatible reports whether a Gateway server's declared protocol is
+// compatible with the effective protocol of the Service port it resolves to.
+func protocolsCompatible(serverProtocol, servicePortProtocol string) bool {
+	prefixes, ok := protocolPrefixes[strings.ToUpper(serverProtocol)]
+	if !ok {
+		// Unknown/uncommon protocol (e.g. Mongo, MySQL); nothing to compare against.
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.EqualFold(prefix, servicePortProtocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectSelectedServicePorts finds the TCP ports, names and appProtocols offered by
+// Kubernetes Services that select pods matching selector, returning the number of pods
+// that matched the selector along with the ports discovered. It is shared by the ingress
+// and egress gateway port analyzers.
+func collectSelectedServicePorts(selector map[string]string, c analysis.Context) (map[uint32]servicePortInfo, int) {
+	servicePorts := map[uint32]servicePortInfo{}
 	gwSelectorMatches := 0
 
-	// For pods selected by gw.Selector, find Services that select them and remember those ports
-	gwSelector := k8s_labels.SelectorFromSet(gw.Selector)
+	gwSelector := k8s_labels.SelectorFromSet(selector)
 	c.ForEach(collections.K8SCoreV1Pods.Name(), func(rPod *resource.Instance) bool {
 		pod := rPod.Message.(*v1.Pod)
 		podLabels := k8s_labels.Set(pod.ObjectMeta.Labels)
@@ -96,7 +216,15 @@ func (*IngressGatewayPortAnalyzer) analyzeGateway(r *resource.Instance, c analys
 				if svcSelector.Matches(podLabels) {
 					for _, port := range service.Ports {
 						if port.Protocol == "TCP" {
-							servicePorts[uint32(port.Port)] = true
+							appProtocol := ""
+							if port.AppProtocol != nil {
+								appProtocol = *port.AppProtocol
+							}
+							servicePorts[uint32(port.Port)] = servicePortInfo{
+								serviceName: rSvc.Metadata.FullName.Name.String(),
+								name:        port.Name,
+								appProtocol: appProtocol,
+							}
 						}
 					}
 				}
@@ -106,27 +234,5 @@ func (*IngressGatewayPortAnalyzer) analyzeGateway(r *resource.Instance, c analys
 		return true
 	})
 
-	if gwSelectorMatches == 0 {
-		// We found no service for the Gateway's workload selector.  If the Gateway doesn't select
-		// the Istio system ingress gateway complain about a missing referenced resource.  (We
-		// don't want to complain about missing system resources, because a user may want to analyze
-		// only his own application files.)
-		// https://github.com/istio/istio/issues/19579 should make this unnecessary
-		if len(gw.Selector) != 1 || gw.Selector["istio"] != "ingressgateway" {
-			c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(), msg.NewReferencedResourceNotFound(r, "selector", gwSelector.String()))
-			return
-		}
-		// The unreferenced Ingress is the System ingress, pretend we have found it.
-		servicePorts = defaultIngressGatewayPorts
-	}
-
-	// Check each Gateway port against what the workload ingress service offers
-	for _, server := range gw.Servers {
-		if server.Port != nil {
-			_, ok := servicePorts[server.Port.Number]
-			if !ok {
-				c.Report(collections.IstioNetworkingV1Alpha3Gateways.Name(), msg.NewGatewayPortNotOnWorkload(r, gwSelector.String(), int(server.Port.Number)))
-			}
-		}
-	}
+	return servicePorts, gwSelectorMatches
 }
@@ -0,0 +1,93 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/testutil"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+)
+
+func TestIngressGatewayPortProtocolMismatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := testutil.NewContext(t, "testdata/gateway-protocol-mismatch.yaml")
+	a := &gateway.IngressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.GatewayPortProtocolMismatch))
+}
+
+func TestIngressGatewayPortClean(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A port that matches both number and protocol shouldn't be reported at all.
+	ctx := testutil.NewContext(t, "testdata/gateway-port-clean.yaml")
+	a := &gateway.IngressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
+
+func TestIngressGatewaySelectorNotFound(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A selector that matches no workload pods at all (and isn't the system ingress
+	// gateway's default selector) is a missing referenced resource, not a port problem.
+	ctx := testutil.NewContext(t, "testdata/gateway-selector-not-found.yaml")
+	a := &gateway.IngressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.ReferencedResourceNotFound))
+}
+
+func TestIngressAndEgressGatewayPortAnalyzersDoNotDoubleReport(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// EgressGatewayPortAnalyzer's own fixture (selector app: my-egress-controller, a
+	// genuine port-9999 mismatch) must be reported exactly once when both gateway port
+	// analyzers run together, as they do in production via analyzers.All().
+	ctx := testutil.NewContext(t, "testdata/egress-gateway-port.yaml")
+	ingress := &gateway.IngressGatewayPortAnalyzer{}
+	egress := &gateway.EgressGatewayPortAnalyzer{}
+	ingress.Analyze(ctx)
+	egress.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.GatewayPortNotOnWorkload))
+}
+
+func TestIngressGatewayPortProtocolMismatchBareServicePortName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A Service port named exactly "tcp" (no "-suffix") is just as valid as "tcp-foo" and
+	// must still be caught as incompatible with a gateway server declaring HTTPS.
+	ctx := testutil.NewContext(t, "testdata/gateway-protocol-mismatch-bare-name.yaml")
+	a := &gateway.IngressGatewayPortAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(1))
+	g.Expect(msgs[0].Type).To(Equal(msg.GatewayPortProtocolMismatch))
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	v1 "k8s.io/api/core/v1"
+	k8s_labels "k8s.io/apimachinery/pkg/labels"
+	k8s "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"istio.io/istio/galley/pkg/config/analysis"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+	"istio.io/istio/galley/pkg/config/resource"
+	"istio.io/istio/galley/pkg/config/schema/collection"
+	"istio.io/istio/galley/pkg/config/schema/collections"
+)
+
+// The annotation the Istio gateway controller stamps on the Kubernetes Service it
+// provisions on behalf of a Gateway API Gateway resource.
+const gatewayServiceAnnotation = "internal.istio.io/gateway-service"
+
+// The label the Istio gateway controller stamps on the pods of the deployment it
+// provisions on behalf of a Gateway API Gateway resource.
+const gatewayNameLabel = "istio.io/gateway-name"
+
+// GatewayAPIPortAnalyzer checks a Gateway API Gateway's listener ports against the
+// Kubernetes Service provisioned for it by the Istio gateway controller.
+type GatewayAPIPortAnalyzer struct{}
+
+var (
+	// gatewayClasses known to be handled by the Istio gateway controller.
+	knownGatewayClasses = map[string]bool{
+		"istio": true,
+	}
+
+	// (compile-time check that we implement the interface)
+	_ analysis.Analyzer = &GatewayAPIPortAnalyzer{}
+)
+
+// Metadata implements analysis.Analyzer
+func (*GatewayAPIPortAnalyzer) Metadata() analysis.Metadata {
+	return analysis.Metadata{
+		Name:        "gateway.GatewayAPIPortAnalyzer",
+		Description: "Checks a Gateway API gateway's listener ports against the Kubernetes service provisioned for it",
+		Inputs: collection.Names{
+			collections.K8SGatewayApiV1Alpha2Gateways.Name(),
+			collections.K8SCoreV1Pods.Name(),
+			collections.K8SCoreV1Services.Name(),
+		},
+	}
+}
+
+// Analyze implements analysis.Analyzer
+func (s *GatewayAPIPortAnalyzer) Analyze(c analysis.Context) {
+	c.ForEach(collections.K8SGatewayApiV1Alpha2Gateways.Name(), func(r *resource.Instance) bool {
+		s.analyzeGateway(r, c)
+		return true
+	})
+}
+
+func (*GatewayAPIPortAnalyzer) analyzeGateway(r *resource.Instance, c analysis.Context) {
+	gw := r.Message.(*k8s.GatewaySpec)
+
+	if !knownGatewayClasses[string(gw.GatewayClassName)] {
+		c.Report(collections.K8SGatewayApiV1Alpha2Gateways.Name(),
+			msg.NewReferencedResourceNotFound(r, "gatewayClassName", string(gw.GatewayClassName)))
+		return
+	}
+
+	// NOTE: addresses[].value of type Hostname is an arbitrary FQDN per the Gateway API
+	// spec -- it isn't required to name a Kubernetes Service in this cluster (and
+	// wouldn't be namespace-scoped if it did), so there's nothing in-cluster we can
+	// reliably validate it against. We deliberately don't attempt to "resolve" it here.
+
+	// Services provisioned by the Istio gateway controller are stamped with an
+	// annotation pointing back at the Gateway that caused them to be created.
+	servicePorts := map[uint32]bool{}
+	gatewayServiceFound := false
+	c.ForEach(collections.K8SCoreV1Services.Name(), func(rSvc *resource.Instance) bool {
+		if string(rSvc.Metadata.FullName.Namespace) != r.Metadata.FullName.Namespace.String() {
+			return true
+		}
+		if rSvc.Metadata.Annotations[gatewayServiceAnnotation] != r.Metadata.FullName.Name.String() {
+			return true
+		}
+		gatewayServiceFound = true
+		service := rSvc.Message.(*v1.ServiceSpec)
+		for _, port := range service.Ports {
+			if port.Protocol == "TCP" {
+				servicePorts[uint32(port.Port)] = true
+			}
+		}
+		return true
+	})
+
+	if !gatewayServiceFound {
+		// Fall back to the workload selected via the gateway-name label, mirroring
+		// the selector based reconciliation used for networking.istio.io Gateways.
+		gwSelector := k8s_labels.SelectorFromSet(map[string]string{gatewayNameLabel: r.Metadata.FullName.Name.String()})
+		c.ForEach(collections.K8SCoreV1Pods.Name(), func(rPod *resource.Instance) bool {
+			pod := rPod.Message.(*v1.Pod)
+			if !gwSelector.Matches(k8s_labels.Set(pod.ObjectMeta.Labels)) {
+				return true
+			}
+			c.ForEach(collections.K8SCoreV1Services.Name(), func(rSvc *resource.Instance) bool {
+				if string(rSvc.Metadata.FullName.Namespace) != pod.ObjectMeta.Namespace {
+					return true
+				}
+				service := rSvc.Message.(*v1.ServiceSpec)
+				if k8s_labels.SelectorFromSet(service.Selector).Matches(k8s_labels.Set(pod.ObjectMeta.Labels)) {
+					for _, port := range service.Ports {
+						if port.Protocol == "TCP" {
+							servicePorts[uint32(port.Port)] = true
+						}
+					}
+				}
+				return true
+			})
+			return true
+		})
+	}
+
+	for _, listener := range gw.Listeners {
+		if _, ok := servicePorts[uint32(listener.Port)]; !ok {
+			c.Report(collections.K8SGatewayApiV1Alpha2Gateways.Name(),
+				msg.NewGatewayAPIPortNotOnWorkload(r, string(listener.Name), int(listener.Port)))
+		}
+	}
+}
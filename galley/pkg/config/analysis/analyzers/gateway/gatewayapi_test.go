@@ -0,0 +1,66 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/testutil"
+	"istio.io/istio/galley/pkg/config/analysis/diag"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+)
+
+func TestGatewayAPIPort(t *testing.T) {
+	cases := []struct {
+		name     string
+		testdata string
+		want     []diag.MessageType
+	}{
+		{
+			name:     "port not on workload",
+			testdata: "testdata/gateway-api-port.yaml",
+			want:     []diag.MessageType{msg.GatewayAPIPortNotOnWorkload},
+		},
+		{
+			name:     "clean, no findings",
+			testdata: "testdata/gateway-api-port-clean.yaml",
+			want:     nil,
+		},
+		{
+			name:     "unknown gatewayClassName",
+			testdata: "testdata/gateway-api-unknown-class.yaml",
+			want:     []diag.MessageType{msg.ReferencedResourceNotFound},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			ctx := testutil.NewContext(t, tt.testdata)
+			a := &gateway.GatewayAPIPortAnalyzer{}
+			a.Analyze(ctx)
+
+			msgs := ctx.Reports()
+			g.Expect(msgs).To(HaveLen(len(tt.want)))
+			for i, wantType := range tt.want {
+				g.Expect(msgs[i].Type).To(Equal(wantType))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/gateway"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/testutil"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+)
+
+func TestGatewayTLSCredential(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := testutil.NewContext(t, "testdata/gateway-tls-credential.yaml")
+	a := &gateway.GatewayTLSCredentialAnalyzer{}
+	a.Analyze(ctx)
+
+	msgs := ctx.Reports()
+	g.Expect(msgs).To(HaveLen(2))
+	g.Expect(msgs[0].Type).To(Equal(msg.GatewayTLSCredentialNotFound))
+	g.Expect(msgs[1].Type).To(Equal(msg.GatewayTLSCredentialInvalid))
+}
+
+func TestGatewayTLSCredentialClean(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A valid kubernetes.io/tls SIMPLE secret, a valid istio.io/key-and-cert MUTUAL
+	// secret with a cacert entry, and a PASSTHROUGH server with no credentialName at
+	// all shouldn't report anything.
+	ctx := testutil.NewContext(t, "testdata/gateway-tls-credential-clean.yaml")
+	a := &gateway.GatewayTLSCredentialAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
+
+func TestGatewayTLSCredentialNoMatchingPod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// A Gateway selecting the system ingress gateway, analyzed without its pods present
+	// in the snapshot (the common case for `istioctl analyze` on a user's own files),
+	// must not be reported as a missing TLS credential.
+	ctx := testutil.NewContext(t, "testdata/gateway-tls-credential-no-pod.yaml")
+	a := &gateway.GatewayTLSCredentialAnalyzer{}
+	a.Analyze(ctx)
+
+	g.Expect(ctx.Reports()).To(BeEmpty())
+}
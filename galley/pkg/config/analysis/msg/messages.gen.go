@@ -0,0 +1,88 @@
+// GENERATED FILE -- DO NOT EDIT
+//
+// Generated from messages.yaml
+
+package msg
+
+import (
+	"istio.io/istio/galley/pkg/config/analysis/diag"
+	"istio.io/istio/galley/pkg/config/resource"
+)
+
+var (
+	// GatewayAPIPortNotOnWorkload defines a diag.MessageType for message "GatewayAPIPortNotOnWorkload".
+	// Description: There is no workload port matching the Gateway API listener port
+	GatewayAPIPortNotOnWorkload = diag.NewMessageType(diag.Warning, "IST0161", "The gateway API listener %q refers to a port (%d) that is not exposed on the workload")
+
+	// GatewayPortProtocolMismatch defines a diag.MessageType for message "GatewayPortProtocolMismatch".
+	// Description: A gateway server's protocol does not match the named/appProtocol of the Service port it resolves to
+	GatewayPortProtocolMismatch = diag.NewMessageType(diag.Warning, "IST0162",
+		"The gateway server declares protocol %q but the matching port %d on service %q is %q")
+
+	// ConflictingGatewayHost defines a diag.MessageType for message "ConflictingGatewayHost".
+	// Description: Two gateways sharing a workload selector bind the same port/protocol to overlapping hosts
+	ConflictingGatewayHost = diag.NewMessageType(diag.Warning, "IST0163",
+		"This gateway's host %q conflicts with host %q on gateway %q, which shares its workload selector and port/protocol")
+
+	// GatewayTLSCredentialNotFound defines a diag.MessageType for message "GatewayTLSCredentialNotFound".
+	// Description: A gateway server's TLS credentialName does not resolve to a Secret in the gateway workload's namespace
+	GatewayTLSCredentialNotFound = diag.NewMessageType(diag.Error, "IST0164",
+		"The gateway server's TLS credentialName %q does not resolve to a Secret in the gateway workload's namespace")
+
+	// GatewayTLSCredentialInvalid defines a diag.MessageType for message "GatewayTLSCredentialInvalid".
+	// Description: The Secret a gateway server's TLS credentialName resolves to is not usable as configured
+	GatewayTLSCredentialInvalid = diag.NewMessageType(diag.Error, "IST0165",
+		"The gateway server's TLS credentialName %q resolves to an invalid Secret: %s")
+)
+
+// NewGatewayAPIPortNotOnWorkload returns a new diag.Message based on GatewayAPIPortNotOnWorkload.
+func NewGatewayAPIPortNotOnWorkload(r *resource.Instance, listenerName string, port int) diag.Message {
+	return diag.NewMessage(
+		GatewayAPIPortNotOnWorkload,
+		r,
+		listenerName,
+		port,
+	)
+}
+
+// NewGatewayPortProtocolMismatch returns a new diag.Message based on GatewayPortProtocolMismatch.
+func NewGatewayPortProtocolMismatch(r *resource.Instance, declaredProtocol, servicePortProtocol, serviceName string, port int) diag.Message {
+	return diag.NewMessage(
+		GatewayPortProtocolMismatch,
+		r,
+		declaredProtocol,
+		port,
+		serviceName,
+		servicePortProtocol,
+	)
+}
+
+// NewConflictingGatewayHost returns a new diag.Message based on ConflictingGatewayHost.
+func NewConflictingGatewayHost(r *resource.Instance, otherGateway, host, otherHost string) diag.Message {
+	return diag.NewMessage(
+		ConflictingGatewayHost,
+		r,
+		host,
+		otherHost,
+		otherGateway,
+	)
+}
+
+// NewGatewayTLSCredentialNotFound returns a new diag.Message based on GatewayTLSCredentialNotFound.
+func NewGatewayTLSCredentialNotFound(r *resource.Instance, credentialName string) diag.Message {
+	return diag.NewMessage(
+		GatewayTLSCredentialNotFound,
+		r,
+		credentialName,
+	)
+}
+
+// NewGatewayTLSCredentialInvalid returns a new diag.Message based on GatewayTLSCredentialInvalid.
+func NewGatewayTLSCredentialInvalid(r *resource.Instance, credentialName, reason string) diag.Message {
+	return diag.NewMessage(
+		GatewayTLSCredentialInvalid,
+		r,
+		credentialName,
+		reason,
+	)
+}
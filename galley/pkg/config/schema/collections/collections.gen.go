@@ -0,0 +1,44 @@
+// GENERATED FILE -- DO NOT EDIT
+//
+
+package collections
+
+import (
+	"istio.io/istio/galley/pkg/config/schema/collection"
+	"istio.io/istio/galley/pkg/config/schema/resource"
+	"istio.io/istio/pkg/config/validation"
+)
+
+var (
+	// K8SGatewayApiV1Alpha2Gateways describes the collection k8s/gateway.networking.k8s.io/v1alpha2/gateways
+	K8SGatewayApiV1Alpha2Gateways = collection.Builder{
+		Name:     "k8s/gateway.networking.k8s.io/v1alpha2/gateways",
+		Disabled: false,
+		Schema: resource.Builder{
+			Group:         "gateway.networking.k8s.io",
+			Kind:          "Gateway",
+			Plural:        "gateways",
+			Version:       "v1alpha2",
+			Proto:         "k8s.io.gateway_api.v1alpha2.GatewaySpec",
+			ProtoPackage:  "sigs.k8s.io/gateway-api/apis/v1alpha2",
+			ClusterScoped: false,
+			ValidateProto: validation.EmptyValidate,
+		}.Build(),
+	}.MustBuild()
+
+	// K8SCoreV1Secrets describes the collection k8s/core/v1/secrets
+	K8SCoreV1Secrets = collection.Builder{
+		Name:     "k8s/core/v1/secrets",
+		Disabled: false,
+		Schema: resource.Builder{
+			Group:         "",
+			Kind:          "Secret",
+			Plural:        "secrets",
+			Version:       "v1",
+			Proto:         "k8s.io.api.core.v1.Secret",
+			ProtoPackage:  "k8s.io/api/core/v1",
+			ClusterScoped: false,
+			ValidateProto: validation.EmptyValidate,
+		}.Build(),
+	}.MustBuild()
+)